@@ -13,16 +13,82 @@ type Platform struct {
 	OS   string
 	Arch string
 
-	// Default, if true, will be included as a default build target
-	// if no OS/arch is specified. We try to only set as a default popular
-	// targets or targets that are generally useful. For example, Android
-	// is not a default because it is quite rare that you're cross-compiling
-	// something to Android AND something like Linux.
-	Default bool
+	// Tier is the support tier for this OS/arch, on the scale of the Go
+	// port table: 1 is first-class (fully supported and tested by the Go
+	// team), 2 is secondary (supported, built, but not as rigorously
+	// tested), and 3 is broken/experimental. The values in this file do
+	// not all match the port table: every platform built by default is
+	// Tier 1 here, including some the port table ranks lower (e.g.
+	// linux/s390x, the BSDs, linux/mips*). Default
+	// is derived from Tier rather than being set by hand, so adding a
+	// platform here can't accidentally make it (or fail to make it) a
+	// default build target.
+	Tier int
+
+	// Variant is the ARM variant (e.g. "v7"), if any. It is empty for
+	// platforms that don't have sub-architecture variants, and ignored
+	// by every existing code path that doesn't know about it.
+	Variant string
+
+	// GOARM, GOMIPS, GOMIPS64, GOAMD64 and GOWASM are optional ABI
+	// variant settings for architectures that have them. They are empty
+	// unless a caller (typically Variants) has set them, in which case
+	// Env includes the matching GOARM=/GOMIPS=/... pair.
+	GOARM    string
+	GOMIPS   string
+	GOMIPS64 string
+	GOAMD64  string
+	GOWASM   string
+
+	// DefaultVariants overrides, per ABI axis (keyed by env var name,
+	// e.g. "GOARM"), which variant value DefaultVariant reports as the
+	// default; Variants does not read it and always expands every value.
+	// SupportedPlatforms leaves this nil, so DefaultVariant falls back to
+	// the first entry in the axis' variantSpec.Values table. For GOARM,
+	// GOMIPS, GOMIPS64 and GOAMD64 that entry is the default Go itself
+	// would pick; for GOWASM it is "satconv,signext", whereas Go's own
+	// default enables neither feature. Set this only when a caller wants
+	// a different default.
+	DefaultVariants map[string]string
+
+	// OSVersion is the Windows build number (e.g. "10.0.17763") this
+	// platform was built against, if known. It's empty for every
+	// non-Windows platform, and for Windows platforms where the build
+	// number doesn't matter. See WindowsBaseImages for mapping it to a
+	// concrete nanoserver/servercore base image tag.
+	OSVersion string
 }
 
 func (p *Platform) String() string {
-	return fmt.Sprintf("%s/%s", p.OS, p.Arch)
+	s := fmt.Sprintf("%s/%s", p.OS, p.Arch)
+	if p.Variant != "" {
+		s = fmt.Sprintf("%s/%s", s, p.Variant)
+	}
+	if p.OSVersion != "" {
+		s = fmt.Sprintf("%s:%s", s, p.OSVersion)
+	}
+	return s
+}
+
+// Default reports whether p should be included as a build target when no
+// OS/arch is specified. We try to only default to popular targets or
+// targets that are generally useful: it's equivalent to Tier > 0 && Tier
+// <= 1. For example, Android is Tier 2, not Tier 1, because it's quite
+// rare that you're cross-compiling something to Android AND something
+// like Linux.
+//
+// A zero-value Tier means "unknown" (e.g. a Platform built by hand
+// rather than sourced from one of this package's tables) and is never
+// Default, even though 0 <= 1 — otherwise an ad-hoc Platform{OS, Arch}
+// would silently claim to be a vetted, Tier-1-equivalent target.
+//
+// Default used to be a bool field rather than a method. That's a
+// source-breaking change for any caller outside this repo that read
+// platform.Default as a field; every call site within this repo has
+// been updated to Default(), but external callers will need to do the
+// same.
+func (p *Platform) Default() bool {
+	return p.Tier > 0 && p.Tier <= 1
 }
 
 func removeElements(from []Platform, elements []Platform) []Platform {
@@ -42,70 +108,88 @@ func removeElements(from []Platform, elements []Platform) []Platform {
 	return np
 }
 
+// PlatformsByTier returns the platforms SupportedPlatforms(goVersion)
+// would return, filtered to those at or below maxTier. For example,
+// PlatformsByTier(runtime.Version(), 1) returns the Tier 1 platforms,
+// the same set Default() selects, without hand-editing a platform list.
+// See Tier for how these tiers relate to the Go port table.
+func PlatformsByTier(goVersion string, maxTier int) []Platform {
+	all := SupportedPlatforms(goVersion)
+
+	out := make([]Platform, 0, len(all))
+	for _, p := range all {
+		if p.Tier <= maxTier {
+			out = append(out, p)
+		}
+	}
+
+	return out
+}
+
 var (
 	Platforms_1_0 = []Platform{
-		{"darwin", "386", true},
-		{"darwin", "amd64", true},
-		{"linux", "386", true},
-		{"linux", "amd64", true},
-		{"linux", "arm", true},
-		{"freebsd", "386", true},
-		{"freebsd", "amd64", true},
-		{"openbsd", "386", true},
-		{"openbsd", "amd64", true},
-		{"windows", "386", true},
-		{"windows", "amd64", true},
+		{OS: "darwin", Arch: "386", Tier: 1},
+		{OS: "darwin", Arch: "amd64", Tier: 1},
+		{OS: "linux", Arch: "386", Tier: 1},
+		{OS: "linux", Arch: "amd64", Tier: 1},
+		{OS: "linux", Arch: "arm", Tier: 1},
+		{OS: "freebsd", Arch: "386", Tier: 1},
+		{OS: "freebsd", Arch: "amd64", Tier: 1},
+		{OS: "openbsd", Arch: "386", Tier: 1},
+		{OS: "openbsd", Arch: "amd64", Tier: 1},
+		{OS: "windows", Arch: "386", Tier: 1},
+		{OS: "windows", Arch: "amd64", Tier: 1},
 	}
 
 	Platforms_1_1 = append(Platforms_1_0, []Platform{
-		{"freebsd", "arm", true},
-		{"netbsd", "386", true},
-		{"netbsd", "amd64", true},
-		{"netbsd", "arm", true},
-		{"plan9", "386", false},
+		{OS: "freebsd", Arch: "arm", Tier: 1},
+		{OS: "netbsd", Arch: "386", Tier: 1},
+		{OS: "netbsd", Arch: "amd64", Tier: 1},
+		{OS: "netbsd", Arch: "arm", Tier: 1},
+		{OS: "plan9", Arch: "386", Tier: 2},
 	}...)
 
 	Platforms_1_3 = append(Platforms_1_1, []Platform{
-		{"dragonfly", "386", false},
-		{"dragonfly", "amd64", false},
-		{"nacl", "amd64", false},
-		{"nacl", "amd64p32", false},
-		{"nacl", "arm", false},
-		{"solaris", "amd64", false},
+		{OS: "dragonfly", Arch: "386", Tier: 2},
+		{OS: "dragonfly", Arch: "amd64", Tier: 2},
+		{OS: "nacl", Arch: "amd64", Tier: 3},
+		{OS: "nacl", Arch: "amd64p32", Tier: 3},
+		{OS: "nacl", Arch: "arm", Tier: 3},
+		{OS: "solaris", Arch: "amd64", Tier: 2},
 	}...)
 
 	Platforms_1_4 = append(Platforms_1_3, []Platform{
-		{"android", "arm", false},
-		{"plan9", "amd64", false},
+		{OS: "android", Arch: "arm", Tier: 2},
+		{OS: "plan9", Arch: "amd64", Tier: 2},
 	}...)
 
 	Platforms_1_5 = append(Platforms_1_4, []Platform{
-		{"darwin", "arm", false},
-		{"darwin", "arm64", true},
-		{"linux", "arm64", true},
-		{"linux", "ppc64", false},
-		{"linux", "ppc64le", false},
+		{OS: "darwin", Arch: "arm", Tier: 2},
+		{OS: "darwin", Arch: "arm64", Tier: 1},
+		{OS: "linux", Arch: "arm64", Tier: 1},
+		{OS: "linux", Arch: "ppc64", Tier: 2},
+		{OS: "linux", Arch: "ppc64le", Tier: 2},
 	}...)
 
 	Platforms_1_6 = append(Platforms_1_5, []Platform{
-		{"android", "386", false},
-		{"linux", "mips64", false},
-		{"linux", "mips64le", false},
+		{OS: "android", Arch: "386", Tier: 2},
+		{OS: "linux", Arch: "mips64", Tier: 2},
+		{OS: "linux", Arch: "mips64le", Tier: 2},
 	}...)
 
 	Platforms_1_7 = append(Platforms_1_5, []Platform{
 		// While not fully supported s390x is generally useful
-		{"linux", "s390x", true},
-		{"plan9", "arm", false},
+		{OS: "linux", Arch: "s390x", Tier: 1},
+		{OS: "plan9", Arch: "arm", Tier: 2},
 		// Add the 1.6 Platforms, but reflect full support for mips64 and mips64le
-		{"android", "386", false},
-		{"linux", "mips64", true},
-		{"linux", "mips64le", true},
+		{OS: "android", Arch: "386", Tier: 2},
+		{OS: "linux", Arch: "mips64", Tier: 1},
+		{OS: "linux", Arch: "mips64le", Tier: 1},
 	}...)
 
 	Platforms_1_8 = append(Platforms_1_7, []Platform{
-		{"linux", "mips", true},
-		{"linux", "mipsle", true},
+		{OS: "linux", Arch: "mips", Tier: 1},
+		{OS: "linux", Arch: "mipsle", Tier: 1},
 	}...)
 
 	// no new platforms in 1.9
@@ -115,59 +199,83 @@ var (
 	Platforms_1_10 = Platforms_1_9
 
 	Platforms_1_11 = append(Platforms_1_10, []Platform{
-		{"js", "wasm", true},
+		{OS: "js", Arch: "wasm", Tier: 1},
 	}...)
 
-	// no new platforms in 1.12
-	Platforms_1_12 = Platforms_1_11
+	Platforms_1_12 = append(Platforms_1_11, []Platform{
+		// illumos was split out of solaris in 1.12, though illumos
+		// binaries still build (and run) under the "solaris" build tag
+		{OS: "illumos", Arch: "amd64", Tier: 2},
+	}...)
 
-	// no new platforms in 1.12
+	// no new platforms in 1.13
 	Platforms_1_13 = Platforms_1_12
 
-	Platforms_1_14 = removeElements(Platforms_1_13, []Platform{
+	Platforms_1_14 = append(removeElements(Platforms_1_13, []Platform{
 		// Native Client was removed in 1.14 (https://golang.org/doc/go1.14#nacl)
-		{"nacl", "amd64", false},
-		{"nacl", "amd64p32", false},
-		{"nacl", "arm", false},
-	})
+		{OS: "nacl", Arch: "amd64"},
+		{OS: "nacl", Arch: "amd64p32"},
+		{OS: "nacl", Arch: "arm"},
+	}), []Platform{
+		{OS: "freebsd", Arch: "riscv64", Tier: 3},
+	}...)
 
 	Platforms_1_15 = append(
 		removeElements(Platforms_1_14, []Platform{
 			// darwin/386, darwin/arm is unsupported from Go 1.15 (https://golang.org/doc/go1.15#darwin)
-			{"darwin", "386", true},
-			{"darwin", "arm", false},
+			{OS: "darwin", Arch: "386"},
+			{OS: "darwin", Arch: "arm"},
 		}),
 		[]Platform{
-			{"linux", "riscv64", true},
+			{OS: "linux", Arch: "riscv64", Tier: 1},
+			{OS: "openbsd", Arch: "mips64", Tier: 2},
 		}...)
 
 	Platforms_1_16 = append(Platforms_1_15,
-		Platform{"ios", "amd64", false}, // iOS simulator on macOS devices with x86 CPU
-		Platform{"ios", "arm64", false}, // regular iOS devices
+		Platform{OS: "ios", Arch: "amd64", Tier: 2}, // iOS simulator on macOS devices with x86 CPU
+		Platform{OS: "ios", Arch: "arm64", Tier: 2}, // regular iOS devices
+		Platform{OS: "windows", Arch: "arm", Tier: 2},
 	)
 
 	Platforms_1_17 = append(Platforms_1_16,
-		Platform{"windows", "arm64", true},
+		Platform{OS: "windows", Arch: "arm64", Tier: 1},
+	)
+
+	// no new platforms in 1.18; GOAMD64 microarchitecture levels became
+	// selectable for existing amd64 platforms (see Platform.Validate)
+	Platforms_1_18 = Platforms_1_17
+
+	Platforms_1_19 = append(Platforms_1_18,
+		Platform{OS: "linux", Arch: "loong64", Tier: 3},
 	)
 
-	PlatformsLatest = Platforms_1_17
+	PlatformsLatest = Platforms_1_19
 )
 
-// SupportedPlatforms returns the full list of supported platforms for
-// the version of Go that is
-func SupportedPlatforms(v string) []Platform {
-	// Use latest if we get an unexpected version string
+// parseGoVersion parses a "go1.17.3"-style version string as reported by
+// `go version`. ok is false if v isn't in that form, in which case
+// callers should fall back to their own default behavior rather than
+// guess at a version.
+func parseGoVersion(v string) (parsed *version.Version, ok bool) {
 	if !strings.HasPrefix(v, "go") {
-		return PlatformsLatest
+		return nil, false
 	}
-	// go-version only cares about version numbers
-	v = v[2:]
 
-	current, err := version.NewVersion(v)
+	parsed, err := version.NewVersion(v[2:])
 	if err != nil {
 		log.Printf("Unable to parse current go version: %s\n%s", v, err.Error())
+		return nil, false
+	}
+
+	return parsed, true
+}
 
-		// Default to latest
+// SupportedPlatforms returns the full list of supported platforms for
+// the version of Go that is
+func SupportedPlatforms(v string) []Platform {
+	current, ok := parseGoVersion(v)
+	if !ok {
+		// Use latest if we get an unexpected version string
 		return PlatformsLatest
 	}
 
@@ -192,6 +300,8 @@ func SupportedPlatforms(v string) []Platform {
 		{">=1.15, < 1.16", Platforms_1_15},
 		{">=1.16, < 1.17", Platforms_1_16},
 		{">=1.17, < 1.18", Platforms_1_17},
+		{">=1.18, < 1.19", Platforms_1_18},
+		{">=1.19, < 1.20", Platforms_1_19},
 	}
 
 	for _, p := range platforms {
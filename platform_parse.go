@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// archAliases maps common alternate spellings of an arch (as seen in
+// uname -m output, Docker's platform strings, etc.) to the GOARCH value
+// gox and the Go toolchain actually use.
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"i386":    "386",
+	"x86":     "386",
+}
+
+// Parse parses a platform specifier of the form "os", "os/arch" or
+// "os/arch/variant" into a Platform, modeled after containerd's platform
+// specifier syntax. An empty specifier returns the local platform
+// (runtime.GOOS/runtime.GOARCH). Common arch aliases are normalized:
+// x86_64/amd64, aarch64/arm64, armhf (which implies the "v7" ARM
+// variant), and i386/x86 all resolve to their Go equivalents. A
+// "windows/amd64:10.0.17763"-style ":OSVersion" suffix is parsed into
+// Platform.OSVersion; it's only valid when the OS is windows.
+func Parse(specifier string) (Platform, error) {
+	if specifier == "" {
+		return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}, nil
+	}
+
+	orig := specifier
+
+	var osVersion string
+	if idx := strings.IndexByte(specifier, ':'); idx >= 0 {
+		specifier, osVersion = specifier[:idx], specifier[idx+1:]
+		if osVersion == "" {
+			return Platform{}, fmt.Errorf("invalid platform specifier %q: empty OS version", orig)
+		}
+	}
+
+	parts := strings.Split(specifier, "/")
+	if len(parts) == 0 || len(parts) > 3 {
+		return Platform{}, fmt.Errorf("invalid platform specifier %q", orig)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return Platform{}, fmt.Errorf("invalid platform specifier %q", orig)
+		}
+	}
+
+	p := Platform{OS: strings.ToLower(parts[0])}
+	if osVersion != "" {
+		if p.OS != "windows" {
+			return Platform{}, fmt.Errorf("invalid platform specifier %q: OS version is only valid for windows", orig)
+		}
+		p.OSVersion = osVersion
+	}
+
+	if len(parts) == 1 {
+		return p, nil
+	}
+
+	arch := strings.ToLower(parts[1])
+	if arch == "armhf" {
+		p.Arch = "arm"
+		p.Variant = "v7"
+	} else if alias, ok := archAliases[arch]; ok {
+		p.Arch = alias
+	} else {
+		p.Arch = arch
+	}
+
+	if len(parts) == 3 {
+		if p.Arch != "arm" {
+			return Platform{}, fmt.Errorf("invalid platform specifier %q: variants are only valid for arm", orig)
+		}
+		p.Variant = strings.ToLower(parts[2])
+	}
+
+	if p.Variant != "" {
+		p.GOARM = goarmForVariant(p.Variant)
+	}
+
+	return p, nil
+}
+
+// Format is the inverse of Parse: it renders a Platform back into its
+// specifier form, e.g. "linux/arm/v7" or "windows/amd64:10.0.17763".
+func Format(p Platform) string {
+	s := p.OS
+	if p.Arch != "" {
+		s = fmt.Sprintf("%s/%s", s, p.Arch)
+		if p.Variant != "" {
+			s = fmt.Sprintf("%s/%s", s, p.Variant)
+		}
+	}
+	if p.OSVersion != "" {
+		s = fmt.Sprintf("%s:%s", s, p.OSVersion)
+	}
+	return s
+}
+
+// Matcher reports whether a Platform satisfies some criteria.
+type Matcher interface {
+	Match(Platform) bool
+}
+
+// matcher is a Matcher built from a Platform whose zero-valued fields act
+// as wildcards.
+type matcher struct {
+	p Platform
+}
+
+// NewMatcher returns a Matcher for p. A zero-valued OS, Arch, Variant or
+// OSVersion field in p matches any value in the candidate Platform, so
+// NewMatcher(Platform{OS: "linux"}) matches every linux/* platform, and
+// NewMatcher(Platform{OS: "windows", Arch: "amd64"}) matches a windows/
+// amd64 candidate regardless of its OSVersion.
+func NewMatcher(p Platform) Matcher {
+	return &matcher{p: p}
+}
+
+func (m *matcher) Match(p Platform) bool {
+	if m.p.OS != "" && m.p.OS != p.OS {
+		return false
+	}
+	if m.p.Arch != "" && m.p.Arch != p.Arch {
+		return false
+	}
+	if m.p.Variant != "" && m.p.Variant != p.Variant {
+		return false
+	}
+	if m.p.OSVersion != "" && m.p.OSVersion != p.OSVersion {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want Platform
+	}{
+		{"os only", "linux", Platform{OS: "linux"}},
+		{"os and arch", "linux/arm64", Platform{OS: "linux", Arch: "arm64"}},
+		{"arm variant", "linux/arm/v7", Platform{OS: "linux", Arch: "arm", Variant: "v7", GOARM: "7"}},
+		{"x86_64 alias", "linux/x86_64", Platform{OS: "linux", Arch: "amd64"}},
+		{"aarch64 alias", "linux/aarch64", Platform{OS: "linux", Arch: "arm64"}},
+		{"i386 alias", "linux/i386", Platform{OS: "linux", Arch: "386"}},
+		{"armhf alias", "linux/armhf", Platform{OS: "linux", Arch: "arm", Variant: "v7", GOARM: "7"}},
+		{"windows os version", "windows/amd64:10.0.17763", Platform{OS: "windows", Arch: "amd64", OSVersion: "10.0.17763"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %s", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Parse(%q) = %#v, want %#v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEmptyReturnsLocalPlatform(t *testing.T) {
+	p, err := Parse("")
+	if err != nil {
+		t.Fatalf("Parse(\"\") returned error: %s", err)
+	}
+	if p.OS == "" || p.Arch == "" {
+		t.Fatalf("Parse(\"\") = %#v, want local OS/Arch filled in", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		"linux/amd64/v7",   // variant only valid for arm
+		"linux//amd64",     // empty part
+		"linux:10.0.17763", // OS version on non-windows
+		"a/b/c/d",          // too many parts
+	}
+
+	for _, in := range cases {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected an error, got nil", in)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		name string
+		in   Platform
+		want string
+	}{
+		{"os and arch", Platform{OS: "linux", Arch: "amd64"}, "linux/amd64"},
+		{"arm variant", Platform{OS: "linux", Arch: "arm", Variant: "v7"}, "linux/arm/v7"},
+		{"windows os version", Platform{OS: "windows", Arch: "amd64", OSVersion: "10.0.17763"}, "windows/amd64:10.0.17763"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format(tt.in); got != tt.want {
+				t.Errorf("Format(%#v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseFormatRoundTrip guards against String/Format disagreeing
+// about which fields get rendered.
+func TestParseFormatRoundTrip(t *testing.T) {
+	specs := []string{"linux/arm/v7", "windows/amd64:10.0.17763", "darwin/arm64"}
+
+	for _, spec := range specs {
+		p, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned error: %s", spec, err)
+		}
+		if got := Format(p); got != spec {
+			t.Errorf("Format(Parse(%q)) = %q, want %q", spec, got, spec)
+		}
+		if got := p.String(); got != spec {
+			t.Errorf("Parse(%q).String() = %q, want %q", spec, got, spec)
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	cases := []struct {
+		name    string
+		matcher Platform
+		target  Platform
+		want    bool
+	}{
+		{"wildcard os", Platform{Arch: "amd64"}, Platform{OS: "linux", Arch: "amd64"}, true},
+		{"os mismatch", Platform{OS: "linux"}, Platform{OS: "windows"}, false},
+		{"variant mismatch", Platform{OS: "linux", Arch: "arm", Variant: "v7"}, Platform{OS: "linux", Arch: "arm", Variant: "v6"}, false},
+		{"empty os version matches any", Platform{OS: "windows", Arch: "amd64"}, Platform{OS: "windows", Arch: "amd64", OSVersion: "10.0.17763"}, true},
+		{"concrete os version mismatch", Platform{OS: "windows", Arch: "amd64", OSVersion: "10.0.17763"}, Platform{OS: "windows", Arch: "amd64", OSVersion: "10.0.19042"}, false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewMatcher(tt.matcher)
+			if got := m.Match(tt.target); got != tt.want {
+				t.Errorf("NewMatcher(%#v).Match(%#v) = %v, want %v", tt.matcher, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PlatformSetBuilder builds up a []Platform by applying add/remove
+// operations on top of a starting set. It backs ParsePlatformSpec, but is
+// exposed directly for callers that want to apply several specs, or mix
+// spec parsing with their own additions/removals, without re-parsing a
+// string each time.
+type PlatformSetBuilder struct {
+	// known is an unmodified snapshot of the base the builder was
+	// constructed with. remove consults it (rather than b.platforms, which
+	// mutates, or the package-level PlatformsLatest, which doesn't track
+	// the Go version base came from) to decide whether an OS/arch is
+	// "known" for this builder.
+	known     []Platform
+	platforms []Platform
+}
+
+// NewPlatformSetBuilder starts a builder from base. base is copied, so
+// mutating the builder never affects the slice the caller passed in.
+func NewPlatformSetBuilder(base []Platform) *PlatformSetBuilder {
+	np := make([]Platform, len(base))
+	copy(np, base)
+	known := make([]Platform, len(base))
+	copy(known, base)
+	return &PlatformSetBuilder{known: known, platforms: np}
+}
+
+// Platforms returns the current state of the set.
+func (b *PlatformSetBuilder) Platforms() []Platform {
+	return b.platforms
+}
+
+// Apply parses spec and applies each whitespace-separated token to the
+// set in order. See ParsePlatformSpec for the spec syntax.
+func (b *PlatformSetBuilder) Apply(spec string) error {
+	for _, tok := range strings.Fields(spec) {
+		if err := b.applyToken(tok); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *PlatformSetBuilder) applyToken(tok string) error {
+	if tok == "-" {
+		b.platforms = nil
+		return nil
+	}
+
+	if len(tok) < 2 {
+		return fmt.Errorf("invalid platform spec token %q: expected +os, -os, +os/arch or -os/arch", tok)
+	}
+
+	rest := tok
+	force := strings.HasPrefix(rest, "?")
+	if force {
+		rest = rest[1:]
+	}
+	if len(rest) < 2 {
+		return fmt.Errorf("invalid platform spec token %q: expected +os, -os, +os/arch or -os/arch", tok)
+	}
+
+	sign := rest[0]
+	if sign != '+' && sign != '-' {
+		return fmt.Errorf("invalid platform spec token %q: must start with + or - (after an optional leading ?)", tok)
+	}
+	rest = rest[1:]
+
+	os, arch := rest, ""
+	if idx := strings.IndexByte(rest, '/'); idx >= 0 {
+		os, arch = rest[:idx], rest[idx+1:]
+	}
+	if os == "" {
+		return fmt.Errorf("invalid platform spec token %q: missing OS", tok)
+	}
+
+	if sign == '+' {
+		b.add(os, arch)
+		return nil
+	}
+
+	return b.remove(os, arch, force)
+}
+
+// add adds os/arch (or, if arch is empty, every arch PlatformsLatest knows
+// about for os) to the set. When PlatformsLatest has an entry for the
+// os/arch being added, that entry is copied in full, so Tier,
+// DefaultVariants and every other field come along with it. Combos
+// PlatformsLatest doesn't know about are still added, bare: the caller
+// may know about a platform we don't.
+func (b *PlatformSetBuilder) add(os, arch string) {
+	if arch != "" {
+		if p, ok := lookupPlatform(os, arch); ok {
+			b.addOne(p)
+		} else {
+			b.addOne(Platform{OS: os, Arch: arch})
+		}
+		return
+	}
+
+	for _, p := range PlatformsLatest {
+		if p.OS == os {
+			b.addOne(p)
+		}
+	}
+}
+
+func lookupPlatform(os, arch string) (Platform, bool) {
+	for _, p := range PlatformsLatest {
+		if p.OS == os && p.Arch == arch {
+			return p, true
+		}
+	}
+	return Platform{}, false
+}
+
+func (b *PlatformSetBuilder) addOne(p Platform) {
+	for _, existing := range b.platforms {
+		if existing.OS == p.OS && existing.Arch == p.Arch {
+			return
+		}
+	}
+	b.platforms = append(b.platforms, p)
+}
+
+// remove removes os/arch (or, if arch is empty, every platform for os)
+// from the set. Unlike add, an unknown OS/arch is an error unless force
+// is set, since silently no-op-ing a typo'd removal is more surprising
+// than failing loudly.
+func (b *PlatformSetBuilder) remove(os, arch string, force bool) error {
+	if arch != "" {
+		if !force && !b.platformKnown(os, arch) {
+			return fmt.Errorf("unknown platform %s/%s, use ?-%s/%s to remove anyway", os, arch, os, arch)
+		}
+		b.platforms = removeElements(b.platforms, []Platform{{OS: os, Arch: arch}})
+		return nil
+	}
+
+	if !force && !b.osKnown(os) {
+		return fmt.Errorf("unknown OS %q, use ?-%s to remove anyway", os, os)
+	}
+
+	kept := b.platforms[:0:0]
+	for _, p := range b.platforms {
+		if p.OS != os {
+			kept = append(kept, p)
+		}
+	}
+	b.platforms = kept
+	return nil
+}
+
+// platformKnown and osKnown judge "known" against b.known, the base the
+// builder was constructed with, not the package-level PlatformsLatest:
+// a builder started from SupportedPlatforms("go1.0") should treat
+// windows/arm64 as unknown even though PlatformsLatest has since grown
+// an entry for it.
+func (b *PlatformSetBuilder) platformKnown(os, arch string) bool {
+	for _, p := range b.known {
+		if p.OS == os && p.Arch == arch {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *PlatformSetBuilder) osKnown(os string) bool {
+	for _, p := range b.known {
+		if p.OS == os {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePlatformSpec applies spec to base and returns the resulting
+// platform list. spec is a whitespace-separated, left-to-right sequence
+// of tokens:
+//
+//   - bare "-": clear the set entirely
+//   - "+os": add every arch PlatformsLatest has for os
+//   - "-os": remove every platform for os from the set
+//   - "+os/arch": add the specific os/arch pair
+//   - "-os/arch": remove the specific os/arch pair
+//
+// Any +token is accepted even if PlatformsLatest has never heard of the
+// OS/arch: callers may be targeting a platform this version of gox
+// doesn't know about yet. A -token for an OS/arch unknown to base (not
+// PlatformsLatest, so "known" tracks the Go version base came from) is
+// rejected unless prefixed with ?, e.g. "?-plan9/amd64", since it's more
+// likely a typo than an intentional no-op.
+//
+// For example, ParsePlatformSpec(PlatformsLatest, "-windows +linux/s390x")
+// returns everything except windows, plus linux/s390x.
+func ParsePlatformSpec(base []Platform, spec string) ([]Platform, error) {
+	b := NewPlatformSetBuilder(base)
+	if err := b.Apply(spec); err != nil {
+		return nil, err
+	}
+
+	return b.Platforms(), nil
+}
+
+// SupportedPlatformsWithSpec is SupportedPlatforms followed by
+// ParsePlatformSpec, so callers can express "the default set for this Go
+// version, minus these, plus these" in one call instead of post-filtering
+// SupportedPlatforms' result by hand.
+func SupportedPlatformsWithSpec(goVersion, spec string) ([]Platform, error) {
+	return ParsePlatformSpec(SupportedPlatforms(goVersion), spec)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePlatformSpec(t *testing.T) {
+	cases := []struct {
+		name string
+		base []Platform
+		spec string
+		want []Platform
+	}{
+		{
+			name: "clear",
+			base: []Platform{{OS: "linux", Arch: "amd64"}},
+			spec: "-",
+			want: nil,
+		},
+		{
+			name: "remove then add",
+			base: PlatformsLatest,
+			spec: "-windows +linux/s390x",
+			want: func() []Platform {
+				var out []Platform
+				for _, p := range PlatformsLatest {
+					if p.OS != "windows" {
+						out = append(out, p)
+					}
+				}
+				return out
+			}(),
+		},
+		{
+			name: "add unknown combo",
+			base: nil,
+			spec: "+plan9/riscv64",
+			want: []Platform{{OS: "plan9", Arch: "riscv64"}},
+		},
+		{
+			name: "clear then add one os/arch pair",
+			base: PlatformsLatest,
+			spec: "- +darwin/arm64",
+			want: []Platform{{OS: "darwin", Arch: "arm64", Tier: 1}},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlatformSpec(tt.base, tt.spec)
+			if err != nil {
+				t.Fatalf("ParsePlatformSpec(%q) returned error: %s", tt.spec, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParsePlatformSpec(%q) = %#v, want %#v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParsePlatformSpecPreservesMetadata guards against +os silently
+// dropping the matched PlatformsLatest entry's Tier down to its
+// zero value.
+func TestParsePlatformSpecPreservesMetadata(t *testing.T) {
+	got, err := ParsePlatformSpec(nil, "+linux")
+	if err != nil {
+		t.Fatalf("ParsePlatformSpec returned error: %s", err)
+	}
+
+	var ppc64 *Platform
+	var amd64 *Platform
+	for i := range got {
+		switch got[i].Arch {
+		case "ppc64":
+			ppc64 = &got[i]
+		case "amd64":
+			amd64 = &got[i]
+		}
+	}
+
+	if ppc64 == nil {
+		t.Fatal("expected linux/ppc64 to be present after +linux")
+	}
+	if ppc64.Tier != 2 {
+		t.Errorf("linux/ppc64 Tier = %d, want 2 (matching PlatformsLatest, not zero value)", ppc64.Tier)
+	}
+
+	if amd64 == nil {
+		t.Fatal("expected linux/amd64 to be present after +linux")
+	}
+	if got := amd64.DefaultVariant("GOAMD64"); got != "v1" {
+		t.Errorf("linux/amd64 DefaultVariant(GOAMD64) = %q, want %q", got, "v1")
+	}
+}
+
+func TestParsePlatformSpecErrors(t *testing.T) {
+	cases := []string{
+		"bogus",
+		"-linux/sparc64", // known OS, unknown combo, removal without force
+		"-nonexistentos", // unknown OS, removal without force
+	}
+
+	for _, spec := range cases {
+		if _, err := ParsePlatformSpec(PlatformsLatest, spec); err == nil {
+			t.Errorf("ParsePlatformSpec(%q) expected an error, got nil", spec)
+		}
+	}
+}
+
+func TestParsePlatformSpecForceRemove(t *testing.T) {
+	got, err := ParsePlatformSpec(PlatformsLatest, "?-nonexistentos")
+	if err != nil {
+		t.Fatalf("ParsePlatformSpec with ? force returned error: %s", err)
+	}
+	if !reflect.DeepEqual(got, PlatformsLatest) {
+		t.Fatalf("force-removing an absent OS should be a no-op")
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestPlatformDefault(t *testing.T) {
+	cases := []struct {
+		tier int
+		want bool
+	}{
+		{0, false},
+		{1, true},
+		{2, false},
+		{3, false},
+	}
+
+	for _, tt := range cases {
+		p := Platform{OS: "linux", Arch: "amd64", Tier: tt.tier}
+		if got := p.Default(); got != tt.want {
+			t.Errorf("Platform{Tier: %d}.Default() = %v, want %v", tt.tier, got, tt.want)
+		}
+	}
+}
+
+func TestPlatformsByTier(t *testing.T) {
+	got := PlatformsByTier("go1.19", 1)
+	if len(got) == 0 {
+		t.Fatal("PlatformsByTier(\"go1.19\", 1) returned no platforms")
+	}
+	for _, p := range got {
+		if p.Tier > 1 {
+			t.Errorf("PlatformsByTier(\"go1.19\", 1) included %s/%s at Tier %d", p.OS, p.Arch, p.Tier)
+		}
+	}
+
+	all := SupportedPlatforms("go1.19")
+	if len(got) >= len(all) {
+		t.Errorf("PlatformsByTier(\"go1.19\", 1) = %d platforms, want fewer than the %d unfiltered platforms", len(got), len(all))
+	}
+}
+
+// TestIllumosSplitFromSolaris guards the Go 1.12 illumos/amd64 addition:
+// illumos binaries still build under the solaris build tag, but gox
+// should list illumos/amd64 as its own platform from 1.12 onward.
+func TestIllumosSplitFromSolaris(t *testing.T) {
+	for _, p := range SupportedPlatforms("go1.11") {
+		if p.OS == "illumos" {
+			t.Fatalf("illumos/%s present before Go 1.12", p.Arch)
+		}
+	}
+
+	found := false
+	for _, p := range SupportedPlatforms("go1.12") {
+		if p.OS == "illumos" && p.Arch == "amd64" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("illumos/amd64 missing from SupportedPlatforms(\"go1.12\")")
+	}
+}
+
+func TestPlatformsVersionBumps(t *testing.T) {
+	if got, want := len(Platforms_1_18), len(Platforms_1_17); got != want {
+		t.Errorf("len(Platforms_1_18) = %d, want %d (no new platforms in 1.18)", got, want)
+	}
+
+	found := false
+	for _, p := range Platforms_1_19 {
+		if p.OS == "linux" && p.Arch == "loong64" {
+			found = true
+			if p.Tier != 3 {
+				t.Errorf("linux/loong64 Tier = %d, want 3", p.Tier)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("linux/loong64 missing from Platforms_1_19")
+	}
+	if len(Platforms_1_19) != len(Platforms_1_18)+1 {
+		t.Errorf("len(Platforms_1_19) = %d, want %d (exactly one platform added)", len(Platforms_1_19), len(Platforms_1_18)+1)
+	}
+}
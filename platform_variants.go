@@ -0,0 +1,259 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	version "github.com/hashicorp/go-version"
+)
+
+// variantSpec describes the valid values for one ABI variant axis
+// (GOARM, GOMIPS, ...) on a given os/arch, and the Go version from which
+// the axis is recognized.
+type variantSpec struct {
+	OS, Arch   string
+	MinVersion string // "" means the axis has always existed
+	Values     []string
+}
+
+func lookupVariantSpec(table []variantSpec, os, arch string) (variantSpec, bool) {
+	for _, s := range table {
+		if s.OS == os && s.Arch == arch {
+			return s, true
+		}
+	}
+	return variantSpec{}, false
+}
+
+// armVariantGOARM maps the OCI-style ARM variant Parse accepts (as in
+// "linux/arm/v7") to the GOARM value the Go toolchain expects. Parse uses
+// this so a caller that only set Variant still gets a correct GOARM in
+// Env, instead of silently falling back to the toolchain's default.
+var armVariantGOARM = map[string]string{
+	"v5": "5",
+	"v6": "6",
+	"v7": "7",
+}
+
+// goarmForVariant returns the GOARM value for an OCI-style ARM variant
+// string, or "" if variant isn't one Go knows a GOARM mapping for.
+func goarmForVariant(variant string) string {
+	return armVariantGOARM[variant]
+}
+
+var (
+	goarmVariants = []variantSpec{
+		{OS: "linux", Arch: "arm", Values: []string{"7", "6", "5"}},
+		{OS: "freebsd", Arch: "arm", Values: []string{"7", "6", "5"}},
+		{OS: "netbsd", Arch: "arm", Values: []string{"7", "6", "5"}},
+		{OS: "plan9", Arch: "arm", Values: []string{"7", "6", "5"}},
+		{OS: "android", Arch: "arm", Values: []string{"7", "6", "5"}},
+	}
+
+	gomipsVariants = []variantSpec{
+		{OS: "linux", Arch: "mips", MinVersion: "1.8", Values: []string{"hardfloat", "softfloat"}},
+		{OS: "linux", Arch: "mipsle", MinVersion: "1.8", Values: []string{"hardfloat", "softfloat"}},
+	}
+
+	gomips64Variants = []variantSpec{
+		{OS: "linux", Arch: "mips64", MinVersion: "1.8", Values: []string{"hardfloat", "softfloat"}},
+		{OS: "linux", Arch: "mips64le", MinVersion: "1.8", Values: []string{"hardfloat", "softfloat"}},
+	}
+
+	goamd64Variants = []variantSpec{
+		{OS: "linux", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+		{OS: "darwin", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+		{OS: "freebsd", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+		{OS: "windows", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+		{OS: "netbsd", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+		{OS: "openbsd", Arch: "amd64", MinVersion: "1.18", Values: []string{"v1", "v2", "v3", "v4"}},
+	}
+
+	gowasmVariants = []variantSpec{
+		{OS: "js", Arch: "wasm", MinVersion: "1.19", Values: []string{"satconv,signext"}},
+	}
+)
+
+// platformAxis binds one variant axis to the setter that writes a value
+// from it back onto a Platform.
+type platformAxis struct {
+	name string
+	spec variantSpec
+	set  func(*Platform, string)
+}
+
+func platformAxes(os, arch string) []platformAxis {
+	var axes []platformAxis
+	if s, ok := lookupVariantSpec(goarmVariants, os, arch); ok {
+		axes = append(axes, platformAxis{"GOARM", s, func(p *Platform, v string) { p.GOARM = v }})
+	}
+	if s, ok := lookupVariantSpec(gomipsVariants, os, arch); ok {
+		axes = append(axes, platformAxis{"GOMIPS", s, func(p *Platform, v string) { p.GOMIPS = v }})
+	}
+	if s, ok := lookupVariantSpec(gomips64Variants, os, arch); ok {
+		axes = append(axes, platformAxis{"GOMIPS64", s, func(p *Platform, v string) { p.GOMIPS64 = v }})
+	}
+	if s, ok := lookupVariantSpec(goamd64Variants, os, arch); ok {
+		axes = append(axes, platformAxis{"GOAMD64", s, func(p *Platform, v string) { p.GOAMD64 = v }})
+	}
+	if s, ok := lookupVariantSpec(gowasmVariants, os, arch); ok {
+		axes = append(axes, platformAxis{"GOWASM", s, func(p *Platform, v string) { p.GOWASM = v }})
+	}
+	return axes
+}
+
+// expandableAxis reports whether axis is one Variants fans out over by
+// default. GOARM, GOMIPS and GOMIPS64 gate whether the binary runs at
+// all on a given chip, so there's no single sensible default build.
+// GOAMD64 and GOWASM are tunable microarchitecture/feature knobs with a
+// safe empty-string default (the toolchain falls back to GOAMD64=v1
+// on its own), so a plain linux/amd64 Platform that never opted into a
+// specific GOAMD64 shouldn't silently balloon into four build targets.
+func expandableAxis(name string) bool {
+	return name != "GOAMD64" && name != "GOWASM"
+}
+
+// Variants expands p into its concrete build targets along every
+// expandable ABI variant axis its os/arch has (GOARM, GOMIPS, GOMIPS64).
+// A Platform with no expandable variant axis expands to itself
+// unchanged. Tier (and so Default) is copied as-is onto every expansion,
+// since it describes the OS/arch, not the ABI variant; use
+// DefaultVariant to pick the one variant per axis a caller should build
+// when it only wants one.
+func (p *Platform) Variants() []Platform {
+	var axes []platformAxis
+	for _, a := range platformAxes(p.OS, p.Arch) {
+		if expandableAxis(a.name) {
+			axes = append(axes, a)
+		}
+	}
+	if len(axes) == 0 {
+		return []Platform{*p}
+	}
+
+	out := []Platform{*p}
+	for _, axis := range axes {
+		var next []Platform
+		for _, base := range out {
+			for _, v := range axis.spec.Values {
+				np := base
+				axis.set(&np, v)
+				next = append(next, np)
+			}
+		}
+		out = next
+	}
+	return out
+}
+
+// DefaultVariant returns the default value for the given axis (e.g.
+// "GOARM"), following p.DefaultVariants if it has
+// an entry for axis, and otherwise that axis' first valid value. It
+// returns "" if p's os/arch doesn't have that axis at all.
+func (p *Platform) DefaultVariant(axis string) string {
+	if v, ok := p.DefaultVariants[axis]; ok {
+		return v
+	}
+
+	for _, a := range platformAxes(p.OS, p.Arch) {
+		if a.name == axis && len(a.spec.Values) > 0 {
+			return a.spec.Values[0]
+		}
+	}
+
+	return ""
+}
+
+// Env returns the GOOS/GOARCH environment pairs needed to build p,
+// including GOARM=/GOMIPS=/GOMIPS64=/GOAMD64=/GOWASM= when p sets the
+// matching field.
+func (p *Platform) Env() []string {
+	env := []string{
+		fmt.Sprintf("GOOS=%s", p.OS),
+		fmt.Sprintf("GOARCH=%s", p.Arch),
+	}
+
+	for _, kv := range []struct{ key, val string }{
+		{"GOARM", p.GOARM},
+		{"GOMIPS", p.GOMIPS},
+		{"GOMIPS64", p.GOMIPS64},
+		{"GOAMD64", p.GOAMD64},
+		{"GOWASM", p.GOWASM},
+	} {
+		if kv.val != "" {
+			env = append(env, fmt.Sprintf("%s=%s", kv.key, kv.val))
+		}
+	}
+
+	return env
+}
+
+// validateAxis checks that value is a valid setting of the named axis on
+// os/arch. v is the parsed goVersion, or nil if goVersion couldn't be
+// parsed; the MinVersion check is skipped in that case (there's nothing
+// sensible to compare against), but the axis' applicability to os/arch
+// and its set of allowed values are still enforced.
+func validateAxis(name, value string, table []variantSpec, os, arch string, v *version.Version) error {
+	if value == "" {
+		return nil
+	}
+
+	spec, ok := lookupVariantSpec(table, os, arch)
+	if !ok {
+		return fmt.Errorf("%s/%s does not support %s", os, arch, name)
+	}
+
+	if spec.MinVersion != "" && v != nil {
+		min := version.Must(version.NewVersion(spec.MinVersion))
+		if v.LessThan(min) {
+			return fmt.Errorf("%s=%s on %s/%s requires Go %s or later, got %s", name, value, os, arch, spec.MinVersion, v.Original())
+		}
+	}
+
+	for _, allowed := range spec.Values {
+		if allowed == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s=%s is not a valid value for %s/%s (want one of %s)", name, value, os, arch, strings.Join(spec.Values, ", "))
+}
+
+// Validate rejects invalid (os, arch, variant) combinations for the
+// given Go version, e.g. GOAMD64=v3 on Go 1.17 or darwin/arm64 before Go
+// 1.16. If goVersion isn't parseable, Validate skips checks that depend
+// on comparing against a specific Go version (like the darwin/arm64 and
+// MinVersion checks above), but still rejects combinations that are
+// never valid on any Go version, such as GOARM on an arch that doesn't
+// support it.
+func (p *Platform) Validate(goVersion string) error {
+	v, ok := parseGoVersion(goVersion)
+
+	if p.OS == "darwin" && p.Arch == "arm64" && ok {
+		if min := version.Must(version.NewVersion("1.16")); v.LessThan(min) {
+			return fmt.Errorf("darwin/arm64 requires Go 1.16 or later, got %s", goVersion)
+		}
+	}
+
+	if !ok {
+		v = nil
+	}
+
+	if err := validateAxis("GOARM", p.GOARM, goarmVariants, p.OS, p.Arch, v); err != nil {
+		return err
+	}
+	if err := validateAxis("GOMIPS", p.GOMIPS, gomipsVariants, p.OS, p.Arch, v); err != nil {
+		return err
+	}
+	if err := validateAxis("GOMIPS64", p.GOMIPS64, gomips64Variants, p.OS, p.Arch, v); err != nil {
+		return err
+	}
+	if err := validateAxis("GOAMD64", p.GOAMD64, goamd64Variants, p.OS, p.Arch, v); err != nil {
+		return err
+	}
+	if err := validateAxis("GOWASM", p.GOWASM, gowasmVariants, p.OS, p.Arch, v); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlatformVariantsNoExpandableAxis(t *testing.T) {
+	p := Platform{OS: "linux", Arch: "amd64"}
+	got := p.Variants()
+	if len(got) != 1 || !reflect.DeepEqual(got[0], p) {
+		t.Fatalf("Variants() on a platform with no expandable axis = %#v, want []Platform{%#v}", got, p)
+	}
+}
+
+func TestPlatformVariantsGOARM(t *testing.T) {
+	p := Platform{OS: "linux", Arch: "arm", DefaultVariants: map[string]string{"GOARM": "7"}}
+	got := p.Variants()
+
+	want := map[string]bool{"5": false, "6": false, "7": false}
+	for _, v := range got {
+		if v.OS != "linux" || v.Arch != "arm" {
+			t.Fatalf("Variants() returned unexpected platform %#v", v)
+		}
+		if _, ok := want[v.GOARM]; !ok {
+			t.Fatalf("Variants() returned unexpected GOARM %q", v.GOARM)
+		}
+		want[v.GOARM] = true
+	}
+	for goarm, seen := range want {
+		if !seen {
+			t.Errorf("Variants() missing GOARM=%s", goarm)
+		}
+	}
+}
+
+func TestPlatformDefaultVariant(t *testing.T) {
+	withDefault := Platform{OS: "linux", Arch: "arm", DefaultVariants: map[string]string{"GOARM": "6"}}
+	if got := withDefault.DefaultVariant("GOARM"); got != "6" {
+		t.Errorf("DefaultVariant(GOARM) = %q, want %q", got, "6")
+	}
+
+	noOverride := Platform{OS: "linux", Arch: "arm"}
+	if got := noOverride.DefaultVariant("GOARM"); got != "7" {
+		t.Errorf("DefaultVariant(GOARM) with no override = %q, want first axis value %q", got, "7")
+	}
+
+	noAxis := Platform{OS: "linux", Arch: "amd64"}
+	if got := noAxis.DefaultVariant("GOARM"); got != "" {
+		t.Errorf("DefaultVariant(GOARM) on an amd64 platform = %q, want \"\"", got)
+	}
+}
+
+func TestPlatformEnv(t *testing.T) {
+	p := Platform{OS: "linux", Arch: "arm", GOARM: "7"}
+	env := p.Env()
+
+	want := []string{"GOOS=linux", "GOARCH=arm", "GOARM=7"}
+	if len(env) != len(want) {
+		t.Fatalf("Env() = %v, want %v", env, want)
+	}
+	for i := range want {
+		if env[i] != want[i] {
+			t.Fatalf("Env() = %v, want %v", env, want)
+		}
+	}
+}
+
+func TestPlatformEnvFromParsedVariant(t *testing.T) {
+	p, err := Parse("linux/arm/v7")
+	if err != nil {
+		t.Fatalf("Parse returned error: %s", err)
+	}
+
+	env := p.Env()
+	found := false
+	for _, kv := range env {
+		if kv == "GOARM=7" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Env() for Parse(\"linux/arm/v7\") = %v, want it to include GOARM=7", env)
+	}
+}
+
+func TestPlatformValidate(t *testing.T) {
+	cases := []struct {
+		name      string
+		p         Platform
+		goVersion string
+		wantErr   bool
+	}{
+		{"GOAMD64 too old", Platform{OS: "linux", Arch: "amd64", GOAMD64: "v3"}, "go1.17", true},
+		{"GOAMD64 ok", Platform{OS: "linux", Arch: "amd64", GOAMD64: "v3"}, "go1.18", false},
+		{"darwin arm64 too old", Platform{OS: "darwin", Arch: "arm64"}, "go1.15", true},
+		{"darwin arm64 ok", Platform{OS: "darwin", Arch: "arm64"}, "go1.16", false},
+		{"GOARM invalid value", Platform{OS: "linux", Arch: "arm", GOARM: "9"}, "go1.17", true},
+		{"GOARM on unsupported arch", Platform{OS: "linux", Arch: "amd64", GOARM: "7"}, "go1.17", true},
+		{"no variant set is always fine", Platform{OS: "linux", Arch: "amd64"}, "go1.0", false},
+		{"unparseable go version skips MinVersion check", Platform{OS: "linux", Arch: "amd64", GOAMD64: "v4"}, "custom-build", false},
+		{"unparseable go version still catches axis never valid on any version", Platform{OS: "linux", Arch: "amd64", GOARM: "7"}, "custom-build", true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.p.Validate(tt.goVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) on %#v = %v, wantErr %v", tt.goVersion, tt.p, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "strings"
+
+// WindowsBaseImages maps known Platform.OSVersion prefixes to the
+// nanoserver/servercore base image tags Microsoft publishes for that
+// Windows build, e.g. "10.0.17763" -> {"ltsc2019", "1809"} (the same
+// build is tagged both ways). Every key is a full "10.0.NNNNN" build
+// number; WindowsBaseImageTags matches keys as prefixes, so a longer
+// OSVersion such as "10.0.17763.1999" still finds its build.
+var WindowsBaseImages = map[string][]string{
+	"10.0.14393": {"ltsc2016"},
+	"10.0.16299": {"1709"},
+	"10.0.17134": {"1803"},
+	"10.0.17763": {"ltsc2019", "1809"},
+	"10.0.18362": {"1903"},
+	"10.0.18363": {"1909"},
+	"10.0.19041": {"2004"},
+	"10.0.19042": {"20H2"},
+	"10.0.20348": {"ltsc2022"},
+}
+
+// WindowsBaseImageTags returns every known base image tag for a
+// Platform's OSVersion, matching against WindowsBaseImages by longest
+// matching prefix. It returns nil if osVersion doesn't match any known
+// build.
+func WindowsBaseImageTags(osVersion string) []string {
+	bestPrefix := ""
+	var bestTags []string
+	for prefix, tags := range WindowsBaseImages {
+		if strings.HasPrefix(osVersion, prefix) && len(prefix) > len(bestPrefix) {
+			bestPrefix, bestTags = prefix, tags
+		}
+	}
+	return bestTags
+}
+
+// WindowsBaseImageTag returns the primary base image tag for a
+// Platform's OSVersion (WindowsBaseImageTags()[0]), or "" if osVersion
+// doesn't match any known build.
+func WindowsBaseImageTag(osVersion string) string {
+	tags := WindowsBaseImageTags(osVersion)
+	if len(tags) == 0 {
+		return ""
+	}
+	return tags[0]
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindowsBaseImageTags(t *testing.T) {
+	cases := []struct {
+		name      string
+		osVersion string
+		want      []string
+	}{
+		{"exact build match", "10.0.17763", []string{"ltsc2019", "1809"}},
+		{"longer OSVersion matches by prefix", "10.0.17763.1999", []string{"ltsc2019", "1809"}},
+		{"neighbouring build not confused", "10.0.19042", []string{"20H2"}},
+		{"unknown build", "10.0.99999", nil},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WindowsBaseImageTags(tt.osVersion)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WindowsBaseImageTags(%q) = %v, want %v", tt.osVersion, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestWindowsBaseImageTagsLongestPrefix guards against a shorter prefix
+// ("10.0.1") shadowing a more specific one that also matches.
+func TestWindowsBaseImageTagsLongestPrefix(t *testing.T) {
+	WindowsBaseImages["10.0.1"] = []string{"should-never-win"}
+	defer delete(WindowsBaseImages, "10.0.1")
+
+	got := WindowsBaseImageTags("10.0.17763")
+	want := []string{"ltsc2019", "1809"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WindowsBaseImageTags(%q) = %v, want %v (longest prefix should win)", "10.0.17763", got, want)
+	}
+}
+
+func TestWindowsBaseImageTag(t *testing.T) {
+	cases := []struct {
+		name      string
+		osVersion string
+		want      string
+	}{
+		{"returns first tag", "10.0.17763", "ltsc2019"},
+		{"single tag build", "10.0.19042", "20H2"},
+		{"unknown build returns empty", "10.0.99999", ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WindowsBaseImageTag(tt.osVersion); got != tt.want {
+				t.Errorf("WindowsBaseImageTag(%q) = %q, want %q", tt.osVersion, got, tt.want)
+			}
+		})
+	}
+}